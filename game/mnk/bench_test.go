@@ -0,0 +1,21 @@
+package mnk
+
+import (
+	"testing"
+
+	"github.com/TarantulaTechnology/MCTSTicTacToe/mcts"
+)
+
+// benchmarkSearch runs a fixed-iteration MCTS search from the empty
+// position of an m,n,k board, so relative timings show how search cost
+// scales with board size.
+func benchmarkSearch(b *testing.B, m, n, k int) {
+	config := mcts.Config{CPuct: 1.41, Budget: mcts.SearchBudget{MaxIterations: 500}, Evaluator: mcts.UniformEvaluator{}}
+	for i := 0; i < b.N; i++ {
+		mcts.MCTS(New(m, n, k), config)
+	}
+}
+
+func BenchmarkMCTS_TicTacToe_3x3x3(b *testing.B)   { benchmarkSearch(b, 3, 3, 3) }
+func BenchmarkMCTS_ConnectFour_9x9x5(b *testing.B) { benchmarkSearch(b, 9, 9, 5) }
+func BenchmarkMCTS_Gomoku_15x15x5(b *testing.B)    { benchmarkSearch(b, 15, 15, 5) }
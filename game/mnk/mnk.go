@@ -0,0 +1,243 @@
+// Package mnk implements the general m,n,k-game family (TicTacToe,
+// Gomoku, Connect-N, ...) as an mcts.GameState, so the same search drives
+// any board size and win length.
+package mnk
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+
+	"github.com/TarantulaTechnology/MCTSTicTacToe/mcts"
+)
+
+type Player int
+
+const (
+	Empty Player = iota
+	X
+	O
+)
+
+func (p Player) String() string {
+	switch p {
+	case X:
+		return "X"
+	case O:
+		return "O"
+	default:
+		return "."
+	}
+}
+
+func other(p Player) Player {
+	if p == X {
+		return O
+	}
+	return X
+}
+
+// Other returns the opposing player.
+func (p Player) Other() Player { return other(p) }
+
+// Action is a move on an m,n,k board: place the current player's stone at
+// (Row, Col).
+type Action struct {
+	Row, Col int
+}
+
+// State is an m,n,k board: M rows, N columns, K stones in a row to win.
+// It tracks the last move made so IsTerminal/GetReward can check for a win
+// by scanning only the lines through that stone, rather than the whole
+// board.
+// State tracks hash, the position's Zobrist hash, incrementally alongside
+// the board so mcts.Transposition can recognize two move orders that reach
+// the same position (see Hash).
+type State struct {
+	board            []Player
+	m, n, k          int
+	player           Player
+	lastRow, lastCol int
+	hash             uint64
+}
+
+// New returns the empty starting position for an m,n,k game, X to move.
+func New(m, n, k int) *State {
+	return &State{
+		board:   make([]Player, m*n),
+		m:       m,
+		n:       n,
+		k:       k,
+		player:  X,
+		lastRow: -1,
+		lastCol: -1,
+	}
+}
+
+func (s *State) Dimensions() (m, n, k int) { return s.m, s.n, s.k }
+func (s *State) CurrentPlayer() Player     { return s.player }
+
+// LastMover returns the player who made the most recent move, i.e. the
+// opponent of CurrentPlayer. Combined with GetReward() != 0 it identifies
+// the winner of a finished game.
+func (s *State) LastMover() Player { return other(s.player) }
+
+func (s *State) at(r, c int) Player { return s.board[r*s.n+c] }
+
+func (s *State) IsEmpty(r, c int) bool {
+	return r >= 0 && r < s.m && c >= 0 && c < s.n && s.at(r, c) == Empty
+}
+
+func (s *State) GetPossibleActions() []mcts.Action {
+	actions := make([]mcts.Action, 0, len(s.board))
+	for r := 0; r < s.m; r++ {
+		for c := 0; c < s.n; c++ {
+			if s.at(r, c) == Empty {
+				actions = append(actions, Action{Row: r, Col: c})
+			}
+		}
+	}
+	return actions
+}
+
+func (s *State) PerformAction(action mcts.Action) mcts.GameState {
+	move := action.(Action)
+	newBoard := make([]Player, len(s.board))
+	copy(newBoard, s.board)
+	newBoard[move.Row*s.n+move.Col] = s.player
+	return &State{
+		board:   newBoard,
+		m:       s.m,
+		n:       s.n,
+		k:       s.k,
+		player:  other(s.player),
+		lastRow: move.Row,
+		lastCol: move.Col,
+		hash:    s.hash ^ zobristKey(move.Row*s.n+move.Col, s.player),
+	}
+}
+
+// Hash implements mcts.Hashable: a Zobrist hash built by XORing in a key
+// for each (cell, player) as stones are placed, so two move orders that
+// place the same stones end up with the same hash regardless of order
+// (XOR is commutative), letting an mcts.Transposition table fold them into
+// one shared Node.
+func (s *State) Hash() uint64 { return s.hash }
+
+// zobristKey derives the key for placing player's stone on cell
+// deterministically from a fixed-point hash (the finalizer from
+// splitmix64), rather than drawing from a stored random table. That avoids
+// having to pre-size a table for boards of unknown m*n up front, while
+// still giving each (cell, player) pair its own effectively-random 64-bit
+// key with good bit dispersion.
+func zobristKey(cell int, player Player) uint64 {
+	x := uint64(cell)*2 + uint64(player)
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	x ^= x >> 31
+	return x
+}
+
+func (s *State) IsTerminal() bool {
+	return s.hasWinAt(s.lastRow, s.lastCol) || s.isFull()
+}
+
+// GetReward follows mcts.GameState's convention: it is the result from
+// the perspective of CurrentPlayer, the player to move in this (terminal)
+// state. Whoever moves into a terminal state either just won or drew, so
+// CurrentPlayer is always the loser or a drawing player, never the
+// winner: -1 if LastMover completed a line, 0 on a draw.
+func (s *State) GetReward() float64 {
+	if s.hasWinAt(s.lastRow, s.lastCol) {
+		return -1
+	}
+	return 0
+}
+
+// hasWinAt checks only the four lines (horizontal, vertical, and both
+// diagonals) passing through (r, c), since any win must run through the
+// most recently placed stone.
+func (s *State) hasWinAt(r, c int) bool {
+	if r < 0 {
+		return false
+	}
+	player := s.at(r, c)
+	if player == Empty {
+		return false
+	}
+	directions := [4][2]int{{0, 1}, {1, 0}, {1, 1}, {1, -1}}
+	for _, d := range directions {
+		count := 1 + s.countDir(r, c, d[0], d[1], player) + s.countDir(r, c, -d[0], -d[1], player)
+		if count >= s.k {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *State) countDir(r, c, dr, dc int, player Player) int {
+	count := 0
+	r, c = r+dr, c+dc
+	for r >= 0 && r < s.m && c >= 0 && c < s.n && s.at(r, c) == player {
+		count++
+		r += dr
+		c += dc
+	}
+	return count
+}
+
+func (s *State) isFull() bool {
+	for _, v := range s.board {
+		if v == Empty {
+			return false
+		}
+	}
+	return true
+}
+
+// wouldWin reports whether placing player's stone at (r, c) on s completes
+// a line of s.k, without mutating s.
+func (s *State) wouldWin(player Player, r, c int) bool {
+	board := make([]Player, len(s.board))
+	copy(board, s.board)
+	board[r*s.n+c] = player
+	hypothetical := &State{board: board, m: s.m, n: s.n, k: s.k, lastRow: r, lastCol: c}
+	return hypothetical.hasWinAt(r, c)
+}
+
+// HeuristicPolicy is an mcts.RolloutPolicy that plays an immediate win if
+// one is available, otherwise blocks the opponent's immediate win,
+// otherwise falls back to a uniformly random legal move. It makes
+// rollouts far less noisy than UniformRandomPolicy at the cost of a
+// little extra work per ply.
+type HeuristicPolicy struct{}
+
+func (HeuristicPolicy) SelectMove(state mcts.GameState, rng *rand.Rand) mcts.Action {
+	s := state.(*State)
+	actions := s.GetPossibleActions()
+	for _, a := range actions {
+		move := a.(Action)
+		if s.wouldWin(s.player, move.Row, move.Col) {
+			return a
+		}
+	}
+	for _, a := range actions {
+		move := a.(Action)
+		if s.wouldWin(other(s.player), move.Row, move.Col) {
+			return a
+		}
+	}
+	return actions[rng.Intn(len(actions))]
+}
+
+func (s *State) String() string {
+	var b strings.Builder
+	for r := 0; r < s.m; r++ {
+		for c := 0; c < s.n; c++ {
+			fmt.Fprintf(&b, "%s ", s.at(r, c))
+		}
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
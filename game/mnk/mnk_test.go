@@ -0,0 +1,83 @@
+package mnk
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/TarantulaTechnology/MCTSTicTacToe/mcts"
+)
+
+func TestGetRewardIsLossOrDrawForSideToMove(t *testing.T) {
+	s := New(3, 3, 3)
+	for _, move := range []Action{{0, 0}, {1, 0}, {0, 1}, {1, 1}, {0, 2}} {
+		s = s.PerformAction(move).(*State)
+	}
+	if !s.IsTerminal() {
+		t.Fatalf("expected a terminal (won) position, got:\n%s", s)
+	}
+	if reward := s.GetReward(); reward != -1 {
+		t.Errorf("GetReward() = %v, want -1 (side to move always lost or drew)", reward)
+	}
+	if s.LastMover() != X {
+		t.Errorf("LastMover() = %v, want X", s.LastMover())
+	}
+}
+
+func TestHeuristicPolicyTakesImmediateWin(t *testing.T) {
+	s := New(3, 3, 3)
+	for _, move := range []Action{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		s = s.PerformAction(move).(*State)
+	}
+	// X has two in a row at (0,0),(0,1); (0,2) completes it.
+	got := HeuristicPolicy{}.SelectMove(s, rand.New(rand.NewSource(1)))
+	if got != (Action{Row: 0, Col: 2}) {
+		t.Errorf("SelectMove() = %v, want the winning move {0 2}", got)
+	}
+}
+
+func TestHeuristicPolicyBlocksOpponentWin(t *testing.T) {
+	s := New(3, 3, 3)
+	for _, move := range []Action{{0, 0}, {1, 0}, {2, 2}, {1, 1}} {
+		s = s.PerformAction(move).(*State)
+	}
+	// O has two in a row at (1,0),(1,1); X must block at (1,2).
+	got := HeuristicPolicy{}.SelectMove(s, rand.New(rand.NewSource(1)))
+	if got != (Action{Row: 1, Col: 2}) {
+		t.Errorf("SelectMove() = %v, want the blocking move {1 2}", got)
+	}
+}
+
+func TestHashIsIndependentOfMoveOrder(t *testing.T) {
+	// Both orders place X at (0,0) and (0,1), O at (1,0) and (1,1) - only
+	// the order of X's own two moves (and O's own two moves) differs, so
+	// they reach the identical final position.
+	firstOrder := New(3, 3, 3)
+	for _, move := range []Action{{0, 0}, {1, 0}, {0, 1}, {1, 1}} {
+		firstOrder = firstOrder.PerformAction(move).(*State)
+	}
+	secondOrder := New(3, 3, 3)
+	for _, move := range []Action{{0, 1}, {1, 1}, {0, 0}, {1, 0}} {
+		secondOrder = secondOrder.PerformAction(move).(*State)
+	}
+	if firstOrder.Hash() != secondOrder.Hash() {
+		t.Errorf("Hash() = %#x and %#x, want equal for the same final position reached via a different move order", firstOrder.Hash(), secondOrder.Hash())
+	}
+}
+
+func TestHashDiffersForDifferentPositions(t *testing.T) {
+	a := New(3, 3, 3).PerformAction(Action{0, 0}).(*State)
+	b := New(3, 3, 3).PerformAction(Action{1, 1}).(*State)
+	if a.Hash() == b.Hash() {
+		t.Errorf("Hash() = %#x for both distinct single-stone positions, want different", a.Hash())
+	}
+}
+
+func TestSimulateReturnsTerminalRange(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for i := 0; i < 50; i++ {
+		value := mcts.Simulate(New(3, 3, 3), mcts.UniformRandomPolicy{}, rng)
+		if value != -1 && value != 0 && value != 1 {
+			t.Fatalf("Simulate() = %v, want one of -1, 0, 1", value)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package mcts
+
+import (
+	"context"
+	"time"
+)
+
+// checkEvery controls how many iterations elapse between deadline checks,
+// trading a little overshoot past the budget for not paying a context/time
+// check on every single playout.
+const checkEvery = 64
+
+// SearchBudget bounds a single search by iteration count, wall-clock time,
+// or an external context.Context, whichever comes first. A zero value
+// means "no limit" for that dimension; leaving all three zero means the
+// search never stops on its own, so callers should set at least one.
+type SearchBudget struct {
+	MaxIterations int
+	MaxDuration   time.Duration
+	Context       context.Context
+}
+
+// deadline returns a context that's Done() once either b.Context is
+// cancelled or b.MaxDuration has elapsed (if set), plus the cleanup the
+// caller must run when the search finishes.
+func (b SearchBudget) deadline() (context.Context, context.CancelFunc) {
+	ctx := b.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if b.MaxDuration > 0 {
+		return context.WithTimeout(ctx, b.MaxDuration)
+	}
+	return context.WithCancel(ctx)
+}
+
+// done reports whether the search should stop: either the budget's
+// deadline has passed, or (when MaxIterations is set) i has reached it.
+func (b SearchBudget) done(ctx context.Context, i int) bool {
+	if b.MaxIterations > 0 && i >= b.MaxIterations {
+		return true
+	}
+	if i%checkEvery != 0 {
+		return false
+	}
+	select {
+	case <-ctx.Done():
+		return true
+	default:
+		return false
+	}
+}
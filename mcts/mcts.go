@@ -0,0 +1,366 @@
+// Package mcts implements a game-agnostic Monte Carlo Tree Search engine.
+// Any game plugs in by implementing GameState; the tree, selection, and
+// backpropagation logic here know nothing about the rules of a particular
+// game.
+package mcts
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// GameState is the minimal interface MCTS needs to search a game tree.
+// GetReward is only meaningful once IsTerminal is true, and is defined
+// from the perspective of the player to move in that terminal state (so
+// for a strictly alternating two-player zero-sum game it is always a loss
+// or a draw, never a win: the player who just moved already won, if
+// anyone did).
+type GameState interface {
+	GetPossibleActions() []Action
+	PerformAction(Action) GameState
+	IsTerminal() bool
+	GetReward() float64
+}
+
+// Action identifies a single legal move. Concrete games define their own
+// comparable action type (e.g. a row/col pair) and pass it around as this
+// interface.
+type Action interface{}
+
+// Evaluator scores a state the way a policy/value network would: priors
+// give a relative preference over the state's possible actions, and value
+// estimates the expected outcome for the player to move, from that
+// player's own perspective, without requiring a rollout to the end of the
+// game.
+type Evaluator interface {
+	Evaluate(state GameState) (priors map[Action]float64, value float64)
+}
+
+// UniformEvaluator spreads prior probability evenly over the legal actions
+// and abstains from a value estimate, so PUCT selection degrades to plain
+// exploration when no stronger evaluator is plugged in.
+type UniformEvaluator struct{}
+
+func (UniformEvaluator) Evaluate(state GameState) (map[Action]float64, float64) {
+	actions := state.GetPossibleActions()
+	priors := make(map[Action]float64, len(actions))
+	if len(actions) == 0 {
+		return priors, 0
+	}
+	p := 1.0 / float64(len(actions))
+	for _, action := range actions {
+		priors[action] = p
+	}
+	return priors, 0
+}
+
+// RolloutPolicy chooses the moves played during a simulation (rollout)
+// from a non-terminal leaf down to a terminal state.
+type RolloutPolicy interface {
+	SelectMove(state GameState, rng *rand.Rand) Action
+}
+
+// UniformRandomPolicy plays a uniformly random legal move at every ply,
+// the classic "light playout" baseline.
+type UniformRandomPolicy struct{}
+
+func (UniformRandomPolicy) SelectMove(state GameState, rng *rand.Rand) Action {
+	actions := state.GetPossibleActions()
+	return actions[rng.Intn(len(actions))]
+}
+
+// Simulate plays state forward with policy until IsTerminal, then returns
+// the result from the perspective of the player to move in the original
+// state. Since GetReward is defined from the perspective of the player to
+// move in the terminal state it's called on, and the side to move
+// alternates every ply, the sign is flipped once per move made during the
+// rollout.
+func Simulate(state GameState, policy RolloutPolicy, rng *rand.Rand) float64 {
+	sign := 1.0
+	for !state.IsTerminal() {
+		state = state.PerformAction(policy.SelectMove(state, rng))
+		sign = -sign
+	}
+	return sign * state.GetReward()
+}
+
+// Config controls a single MCTS search. Evaluator defaults to
+// UniformEvaluator and RolloutPolicy to UniformRandomPolicy when left nil,
+// so a caller can ignore both until they have a real policy/value network
+// to plug in.
+type Config struct {
+	CPuct         float64
+	Budget        SearchBudget
+	Evaluator     Evaluator
+	RolloutPolicy RolloutPolicy
+	Rng           *rand.Rand
+
+	// Transposition, if set, is consulted during Expand so that a GameState
+	// implementing Hashable folds into an existing Node when some other
+	// move order has already reached the same position, instead of
+	// starting a cold subtree for it. Left nil, searches behave exactly as
+	// they did before transposition support existed.
+	Transposition *Transposition
+
+	// BackpropAllParents switches MCTS (not MCTSParallel - see
+	// BackpropagateDAG) from the single-parent Backpropagate walk to
+	// BackpropagateDAG, crediting every move order Transposition has folded
+	// into a shared node rather than just the one that created it. It has
+	// no effect unless Transposition is also set.
+	BackpropAllParents bool
+
+	// UseEvaluatorValue backpropagates a newly expanded node's Evaluator
+	// value directly instead of running a rollout with RolloutPolicy, the
+	// AlphaZero-style policy/value-network approach the Evaluator
+	// interface was built for. It has no effect with UniformEvaluator
+	// (whose value is always 0); it's meant for a non-uniform Evaluator
+	// plugged in to replace rollouts entirely.
+	UseEvaluatorValue bool
+}
+
+func DefaultConfig() Config {
+	return Config{
+		CPuct:         1.41,
+		Budget:        SearchBudget{MaxIterations: 1000},
+		Evaluator:     UniformEvaluator{},
+		RolloutPolicy: UniformRandomPolicy{},
+		Rng:           rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Node is safe for concurrent use: MCTSParallel has many goroutines
+// selecting, expanding, and backpropagating through the same tree, so
+// every field mutated after construction (children, visits, totalReward)
+// is guarded by mu. state, parent, prior, and action are fixed at
+// construction and never written again, so they're read lock-free.
+type Node struct {
+	mu          sync.RWMutex
+	state       GameState
+	parent      *Node
+	action      Action // the move that produced state from parent.state; nil at the root
+	children    []*Node
+	visits      int
+	totalReward float64
+	prior       float64
+
+	// sharedParents holds any additional parents a Transposition table has
+	// folded onto this node beyond the one that first created it (parent),
+	// making the tree below it a DAG rather than strictly a tree. Empty
+	// unless Config.Transposition is in use. Guarded by mu like children.
+	sharedParents []*Node
+}
+
+func NewNode(state GameState, parent *Node, prior float64, action Action) *Node {
+	return &Node{
+		state:    state,
+		parent:   parent,
+		action:   action,
+		children: []*Node{},
+		prior:    prior,
+	}
+}
+
+func (n *Node) State() GameState { return n.state }
+
+// Action returns the move that led to n, or nil for the root.
+func (n *Node) Action() Action { return n.action }
+
+func (n *Node) Children() []*Node {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.children
+}
+
+func (n *Node) Visits() int {
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+	return n.visits
+}
+
+// BestChild returns the most-visited child, the standard way to pick a
+// move once a search is complete.
+func (n *Node) BestChild() *Node {
+	n.mu.RLock()
+	children := n.children
+	n.mu.RUnlock()
+	var best *Node
+	bestVisits := -1
+	for _, child := range children {
+		if v := child.Visits(); v > bestVisits {
+			bestVisits = v
+			best = child
+		}
+	}
+	return best
+}
+
+// PUCTValue implements the AlphaZero-style selection formula
+// Q(s,a) + c_puct * P(s,a) * sqrt(N(s)) / (1 + N(s,a)).
+//
+// n.totalReward accumulates reward from n's own perspective (the player to
+// move in n.state), so it is negated here to read as Q(s,a) from parent's
+// perspective, the one doing the choosing. parent is passed explicitly
+// rather than read from n.parent because a Transposition table can fold n
+// onto more than one move order (see sharedParents): whichever parent is
+// currently iterating over n's Children() may not be the one that first
+// created it, and N(s) must be that caller's own visit count.
+func (n *Node) PUCTValue(cPuct float64, parent *Node) float64 {
+	n.mu.RLock()
+	visits, totalReward := n.visits, n.totalReward
+	n.mu.RUnlock()
+	q := 0.0
+	if visits > 0 {
+		q = -totalReward / float64(visits)
+	}
+	u := cPuct * n.prior * math.Sqrt(float64(parent.Visits())) / float64(1+visits)
+	return q + u
+}
+
+func (n *Node) Select(cPuct float64) *Node {
+	if n.IsLeaf() {
+		return n
+	}
+	bestValue := math.Inf(-1)
+	var bestChild *Node
+	for _, child := range n.Children() {
+		childValue := child.PUCTValue(cPuct, n)
+		if childValue > bestValue {
+			bestValue = childValue
+			bestChild = child
+		}
+	}
+	return bestChild.Select(cPuct)
+}
+
+// Expand asks the evaluator for this node's state once, seeding each new
+// child with its prior P, and returns the evaluator's value estimate for
+// n.state so a caller with Config.UseEvaluatorValue set can backpropagate
+// it directly instead of running a rollout (UniformEvaluator's value is
+// always 0, so that only does something useful once a non-uniform Evaluator
+// is plugged in). If the node was already expanded (possible when two
+// goroutines select the same leaf before either backpropagates), no new
+// children are made, but the value is still computed and returned.
+//
+// When table is non-nil and n.state's children implement Hashable, a child
+// whose position already has a Node elsewhere in the tree is folded in
+// instead of getting a fresh one, so the two move orders share statistics
+// from here on; that folded-in node gets n added as an extra parent once
+// n.mu is released, so Expand never holds its own lock and another node's
+// at the same time.
+func (n *Node) Expand(evaluator Evaluator, table *Transposition) float64 {
+	n.mu.Lock()
+	if len(n.children) > 0 {
+		n.mu.Unlock()
+		_, value := evaluator.Evaluate(n.state)
+		return value
+	}
+	priors, value := evaluator.Evaluate(n.state)
+	actions := n.state.GetPossibleActions()
+	children := make([]*Node, 0, len(actions))
+	var folded []*Node
+	for _, action := range actions {
+		newState := n.state.PerformAction(action)
+		child, wasFolded := n.foldOrCreateChild(newState, action, priors[action], table)
+		if wasFolded {
+			folded = append(folded, child)
+		}
+		children = append(children, child)
+	}
+	n.children = children
+	n.mu.Unlock()
+
+	for _, existing := range folded {
+		existing.addParent(n)
+	}
+	return value
+}
+
+// foldOrCreateChild returns the existing Node for newState from table if
+// one is already being searched via a different move order (reporting it as
+// folded, so the caller can add n as an extra parent once safe to do so),
+// or creates and registers a new child otherwise.
+func (n *Node) foldOrCreateChild(newState GameState, action Action, prior float64, table *Transposition) (child *Node, folded bool) {
+	if table != nil {
+		if hashable, ok := newState.(Hashable); ok {
+			hash := hashable.Hash()
+			if existing, found := table.lookup(hash); found {
+				return existing, true
+			}
+			child := NewNode(newState, n, prior, action)
+			table.store(hash, child)
+			return child, false
+		}
+	}
+	return NewNode(newState, n, prior, action), false
+}
+
+// addParent records n as an additional parent of this node - the
+// Transposition table found it reachable by more than one move order.
+func (n *Node) addParent(p *Node) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.sharedParents = append(n.sharedParents, p)
+}
+
+// Backpropagate records reward, from n's own perspective, at n and every
+// ancestor, negating it at each ply since the side to move alternates on
+// the way up. It walks the parent chain iteratively rather than
+// recursively so a deep tree can't exhaust the goroutine stack.
+func (n *Node) Backpropagate(reward float64) {
+	sign := 1.0
+	for node := n; node != nil; node = node.parent {
+		node.mu.Lock()
+		node.visits++
+		node.totalReward += reward * sign
+		node.mu.Unlock()
+		sign = -sign
+	}
+}
+
+func (n *Node) IsLeaf() bool {
+	return len(n.Children()) == 0
+}
+
+// MCTS searches until config.Budget is exhausted (by iteration count,
+// wall-clock duration, or an external context.Context being cancelled,
+// whichever comes first) and returns the resulting tree; callers pick a
+// move with root.BestChild(). The deadline is only checked every
+// checkEvery iterations, so a cancellation can arrive a little late
+// rather than paying a time/context check on every playout.
+func MCTS(rootState GameState, config Config) *Node {
+	if config.Evaluator == nil {
+		config.Evaluator = UniformEvaluator{}
+	}
+	if config.RolloutPolicy == nil {
+		config.RolloutPolicy = UniformRandomPolicy{}
+	}
+	if config.Rng == nil {
+		config.Rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	ctx, cancel := config.Budget.deadline()
+	defer cancel()
+
+	root := NewNode(rootState, nil, 1.0, nil)
+	for i := 0; !config.Budget.done(ctx, i); i++ {
+		node := root.Select(config.CPuct)
+		var value float64
+		if node.state.IsTerminal() {
+			value = node.state.GetReward()
+		} else {
+			evalValue := node.Expand(config.Evaluator, config.Transposition)
+			if config.UseEvaluatorValue {
+				value = evalValue
+			} else {
+				value = Simulate(node.state, config.RolloutPolicy, config.Rng)
+			}
+		}
+		if config.BackpropAllParents {
+			node.BackpropagateDAG(value)
+		} else {
+			node.Backpropagate(value)
+		}
+	}
+	return root
+}
@@ -0,0 +1,45 @@
+package mcts
+
+import "testing"
+
+// constantValueEvaluator always reports the same value estimate, with
+// uniform priors, standing in for a policy/value network whose value head
+// has already formed an opinion about a position.
+type constantValueEvaluator struct{ value float64 }
+
+func (e constantValueEvaluator) Evaluate(state GameState) (map[Action]float64, float64) {
+	priors, _ := UniformEvaluator{}.Evaluate(state)
+	return priors, e.value
+}
+
+func TestUseEvaluatorValueBackpropagatesEvaluatorValue(t *testing.T) {
+	config := Config{
+		CPuct:             1.41,
+		Budget:            SearchBudget{MaxIterations: 1},
+		Evaluator:         constantValueEvaluator{value: 0.7},
+		RolloutPolicy:     UniformRandomPolicy{},
+		UseEvaluatorValue: true,
+	}
+	// coinFlipState's GetReward is always 0, so a rollout would always
+	// backpropagate 0; only reading the evaluator's value gets 0.7. The
+	// single iteration necessarily selects and expands the (childless)
+	// root itself, so its totalReward directly reflects what got
+	// backpropagated.
+	root := MCTS(coinFlipState{maxDepth: 4}, config)
+	if got := root.totalReward; got != 0.7 {
+		t.Errorf("root.totalReward = %v, want 0.7 (the evaluator's value, not a rollout result)", got)
+	}
+}
+
+func TestWithoutUseEvaluatorValueRunsARollout(t *testing.T) {
+	config := Config{
+		CPuct:         1.41,
+		Budget:        SearchBudget{MaxIterations: 1},
+		Evaluator:     constantValueEvaluator{value: 0.7},
+		RolloutPolicy: UniformRandomPolicy{},
+	}
+	root := MCTS(coinFlipState{maxDepth: 4}, config)
+	if got := root.totalReward; got != 0 {
+		t.Errorf("root.totalReward = %v, want 0 (coinFlipState's rollout reward), not the evaluator's value", got)
+	}
+}
@@ -0,0 +1,135 @@
+package mcts
+
+import (
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// virtualLoss is added to a node's reward (and its visit count incremented)
+// the moment it's chosen during selection, and undone once the real result
+// is backpropagated. PUCTValue reads a child's Q as -totalReward/visits
+// (negated, since totalReward is stored from the child's own perspective),
+// so increasing totalReward here decreases Q as its parent sees it - making
+// the node look temporarily worse, so concurrent workers fan out across the
+// tree instead of all piling onto the same principal variation.
+const virtualLoss = 1.0
+
+// selectWithVirtualLoss walks from n down to a leaf using PUCT, applying a
+// virtual loss to every node it passes through (everything but n itself,
+// which has no incoming edge to penalize).
+func (n *Node) selectWithVirtualLoss(cPuct float64) *Node {
+	node := n
+	for !node.IsLeaf() {
+		bestValue := math.Inf(-1)
+		var bestChild *Node
+		for _, child := range node.Children() {
+			if v := child.PUCTValue(cPuct, node); v > bestValue {
+				bestValue = v
+				bestChild = child
+			}
+		}
+		bestChild.mu.Lock()
+		bestChild.visits++
+		bestChild.totalReward += virtualLoss
+		bestChild.mu.Unlock()
+		node = bestChild
+	}
+	return node
+}
+
+// backpropagateParallel undoes the virtual loss applied during selection
+// and records the real, sign-alternating result at n and every ancestor.
+// The root was never virtual-lossed (selectWithVirtualLoss starts there
+// without penalizing it), so its visit count is incremented here instead.
+func (n *Node) backpropagateParallel(reward float64) {
+	sign := 1.0
+	for node := n; node != nil; node = node.parent {
+		node.mu.Lock()
+		if node.parent == nil {
+			node.visits++
+		} else {
+			node.totalReward -= virtualLoss
+		}
+		node.totalReward += reward * sign
+		node.mu.Unlock()
+		sign = -sign
+	}
+}
+
+// MCTSParallel runs a root-parallel search: workers goroutines share a
+// single tree, each repeatedly selecting (with virtual loss), expanding,
+// simulating, and backpropagating against its own share of
+// config.Budget.MaxIterations, all stopping together once
+// config.Budget's duration or context deadline passes. config.Transposition
+// and config.UseEvaluatorValue are honored the same way MCTS honors them;
+// config.BackpropAllParents is not (see BackpropagateDAG's doc comment) -
+// backpropagation here always walks the single path selectWithVirtualLoss
+// descended.
+func MCTSParallel(rootState GameState, config Config, workers int) *Node {
+	if config.Evaluator == nil {
+		config.Evaluator = UniformEvaluator{}
+	}
+	if config.RolloutPolicy == nil {
+		config.RolloutPolicy = UniformRandomPolicy{}
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	// Never split MaxIterations across more workers than it has iterations
+	// to give: integer division would floor a worker's share to 0, and
+	// SearchBudget.done treats MaxIterations == 0 as "no limit", so that
+	// worker would spin forever instead of stopping.
+	if config.Budget.MaxIterations > 0 && workers > config.Budget.MaxIterations {
+		workers = config.Budget.MaxIterations
+	}
+
+	ctx, cancel := config.Budget.deadline()
+	defer cancel()
+
+	root := NewNode(rootState, nil, 1.0, nil)
+	// Expand the root once, synchronously, before any worker starts. A
+	// childless root is a leaf, and selectWithVirtualLoss only applies a
+	// virtual loss to the child it descends into - never to the node it
+	// started from - so without this, every worker's first iteration can
+	// land on the still-childless root at once, each wasting a playout on
+	// it instead of fanning out the way virtual loss intends.
+	if !root.state.IsTerminal() {
+		root.Expand(config.Evaluator, config.Transposition)
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		share := config.Budget
+		if share.MaxIterations > 0 {
+			share.MaxIterations /= workers
+			if w < config.Budget.MaxIterations%workers {
+				share.MaxIterations++
+			}
+		}
+		rng := rand.New(rand.NewSource(time.Now().UnixNano() + int64(w)))
+		wg.Add(1)
+		go func(share SearchBudget) {
+			defer wg.Done()
+			for i := 0; !share.done(ctx, i); i++ {
+				leaf := root.selectWithVirtualLoss(config.CPuct)
+				var value float64
+				if leaf.state.IsTerminal() {
+					value = leaf.state.GetReward()
+				} else {
+					evalValue := leaf.Expand(config.Evaluator, config.Transposition)
+					if config.UseEvaluatorValue {
+						value = evalValue
+					} else {
+						value = Simulate(leaf.state, config.RolloutPolicy, rng)
+					}
+				}
+				leaf.backpropagateParallel(value)
+			}
+		}(share)
+	}
+	wg.Wait()
+
+	return root
+}
@@ -0,0 +1,30 @@
+package mcts_test
+
+import (
+	"testing"
+
+	"github.com/TarantulaTechnology/MCTSTicTacToe/game/mnk"
+	"github.com/TarantulaTechnology/MCTSTicTacToe/mcts"
+)
+
+// These benchmarks run the same search budget single-threaded and
+// root-parallel; comparing their ns/op (with -benchtime=1s, playouts per
+// wall-clock second) shows how much MCTSParallel actually buys on top of
+// MCTS for a given iteration count.
+func benchConfig() mcts.Config {
+	return mcts.Config{CPuct: 1.41, Budget: mcts.SearchBudget{MaxIterations: 4000}, Evaluator: mcts.UniformEvaluator{}, RolloutPolicy: mnk.HeuristicPolicy{}}
+}
+
+func BenchmarkMCTSSingleThreaded(b *testing.B) {
+	config := benchConfig()
+	for i := 0; i < b.N; i++ {
+		mcts.MCTS(mnk.New(3, 3, 3), config)
+	}
+}
+
+func BenchmarkMCTSParallel4Workers(b *testing.B) {
+	config := benchConfig()
+	for i := 0; i < b.N; i++ {
+		mcts.MCTSParallel(mnk.New(3, 3, 3), config, 4)
+	}
+}
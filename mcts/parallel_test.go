@@ -0,0 +1,127 @@
+package mcts
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+	"time"
+)
+
+// coinFlipState is a minimal two-action game used only to check that
+// MCTSParallel's bookkeeping (total iterations, visit accounting) holds up
+// under concurrent access; it says nothing about search quality.
+type coinFlipState struct {
+	depth, maxDepth int
+}
+
+func (s coinFlipState) GetPossibleActions() []Action { return []Action{0, 1} }
+
+func (s coinFlipState) PerformAction(a Action) GameState {
+	return coinFlipState{depth: s.depth + 1, maxDepth: s.maxDepth}
+}
+
+func (s coinFlipState) IsTerminal() bool   { return s.depth >= s.maxDepth }
+func (s coinFlipState) GetReward() float64 { return 0 }
+
+func TestMCTSParallelVisitAccounting(t *testing.T) {
+	config := Config{CPuct: 1.41, Budget: SearchBudget{MaxIterations: 200}, Evaluator: UniformEvaluator{}, RolloutPolicy: UniformRandomPolicy{}}
+	root := MCTSParallel(coinFlipState{maxDepth: 4}, config, 4)
+	if got := root.Visits(); got != config.Budget.MaxIterations {
+		t.Errorf("root.Visits() = %d, want %d", got, config.Budget.MaxIterations)
+	}
+	// MCTSParallel expands the root before any worker starts, so every
+	// iteration's selection descends into some child rather than racing
+	// to land on a still-childless root; every iteration therefore visits
+	// exactly one direct child, same as it visits the root.
+	total := 0
+	for _, c := range root.Children() {
+		total += c.Visits()
+	}
+	if want := config.Budget.MaxIterations; total != want {
+		t.Errorf("sum of child visits = %d, want %d", total, want)
+	}
+}
+
+// TestMCTSParallelMoreWorkersThanIterations guards against a hang: splitting
+// MaxIterations across more workers than it has iterations to give used to
+// floor some workers' share to 0 via integer division, and
+// SearchBudget.done treats a 0 MaxIterations as "no limit" - so those
+// workers would spin forever and wg.Wait() would never return.
+func TestMCTSParallelMoreWorkersThanIterations(t *testing.T) {
+	config := Config{CPuct: 1.41, Budget: SearchBudget{MaxIterations: 3}, Evaluator: UniformEvaluator{}, RolloutPolicy: UniformRandomPolicy{}}
+
+	done := make(chan *Node, 1)
+	go func() {
+		done <- MCTSParallel(coinFlipState{maxDepth: 4}, config, 8)
+	}()
+
+	select {
+	case root := <-done:
+		if got := root.Visits(); got != config.Budget.MaxIterations {
+			t.Errorf("root.Visits() = %d, want %d", got, config.Budget.MaxIterations)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("MCTSParallel(workers=8, MaxIterations=3) never returned")
+	}
+}
+
+// TestVirtualLossDiscouragesRepeatedSelection checks the direction of the
+// virtual loss bump, not just its bookkeeping: after one worker selects a
+// child, that child must look worse to PUCT so the next worker fans out to
+// a sibling instead of piling onto the same path. A sign error here (adding
+// the loss the wrong way) would make selectWithVirtualLoss pass
+// TestMCTSParallelVisitAccounting while actually making workers converge on
+// the same child even harder than with no virtual loss at all.
+func TestVirtualLossDiscouragesRepeatedSelection(t *testing.T) {
+	root := NewNode(coinFlipState{maxDepth: 2}, nil, 1.0, nil)
+	child0 := NewNode(coinFlipState{depth: 1, maxDepth: 2}, root, 0.5, 0)
+	child1 := NewNode(coinFlipState{depth: 1, maxDepth: 2}, root, 0.5, 1)
+	root.children = []*Node{child0, child1}
+
+	first := root.selectWithVirtualLoss(1.41)
+	if first != child0 {
+		t.Fatalf("first selectWithVirtualLoss() picked %p, want child0 (%p) on a tie", first, child0)
+	}
+	if v := child0.PUCTValue(1.41, root); v >= child1.PUCTValue(1.41, root) {
+		t.Errorf("child0.PUCTValue() = %v, want less than child1.PUCTValue() = %v after child0 took a virtual loss", v, child1.PUCTValue(1.41, root))
+	}
+
+	second := root.selectWithVirtualLoss(1.41)
+	if second != child1 {
+		t.Errorf("second selectWithVirtualLoss() picked the same child again (%p); virtual loss should have pushed it to the other child (%p)", second, child1)
+	}
+}
+
+func TestMCTSRespectsMaxDuration(t *testing.T) {
+	config := Config{
+		CPuct:         1.41,
+		Budget:        SearchBudget{MaxDuration: 10 * time.Millisecond},
+		Evaluator:     UniformEvaluator{},
+		RolloutPolicy: UniformRandomPolicy{},
+		Rng:           rand.New(rand.NewSource(1)),
+	}
+	start := time.Now()
+	root := MCTS(coinFlipState{maxDepth: 4}, config)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("MCTS ran for %v, want roughly the 10ms budget", elapsed)
+	}
+	if root.Visits() == 0 {
+		t.Error("expected at least one iteration within the budget")
+	}
+}
+
+func TestMCTSRespectsCancelledContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	config := Config{
+		CPuct:         1.41,
+		Budget:        SearchBudget{Context: ctx},
+		Evaluator:     UniformEvaluator{},
+		RolloutPolicy: UniformRandomPolicy{},
+		Rng:           rand.New(rand.NewSource(1)),
+	}
+	root := MCTS(coinFlipState{maxDepth: 4}, config)
+	if got := root.Visits(); got != 0 {
+		t.Errorf("root.Visits() = %d, want 0 for an already-cancelled context", got)
+	}
+}
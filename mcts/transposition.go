@@ -0,0 +1,110 @@
+package mcts
+
+import "sync"
+
+// Hashable is implemented by GameState types whose positions can transpose:
+// different move orders reaching the same board. Expand consults it (via a
+// Transposition table) to fold a newly created child into an existing Node
+// for the same position instead of growing a second, statistically colder
+// subtree for it.
+type Hashable interface {
+	Hash() uint64
+}
+
+// Transposition maps a position's hash to the Node already searching it, so
+// two move orders that reach the same position share visit and reward
+// statistics. Folding nodes this way turns the search tree below the shared
+// node into a DAG: that node gets more than one parent (see Node.parent vs
+// Node.sharedParents). Safe for concurrent use by MCTSParallel's workers.
+type Transposition struct {
+	mu            sync.Mutex
+	nodes         map[uint64]*Node
+	hits, lookups int64
+}
+
+// NewTransposition returns an empty transposition table.
+func NewTransposition() *Transposition {
+	return &Transposition{nodes: make(map[uint64]*Node)}
+}
+
+// lookup returns the existing node for hash, if any, and counts the access
+// towards HitRate.
+func (t *Transposition) lookup(hash uint64) (*Node, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lookups++
+	node, ok := t.nodes[hash]
+	if ok {
+		t.hits++
+	}
+	return node, ok
+}
+
+// store records node as the canonical node for hash, unless a concurrent
+// Expand elsewhere already claimed it first (first writer wins; the loser
+// just doesn't get folded in this time, which is harmless).
+func (t *Transposition) store(hash uint64, node *Node) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.nodes[hash]; !ok {
+		t.nodes[hash] = node
+	}
+}
+
+// HitRate returns the fraction of Expand's transposition lookups that found
+// an existing node, so callers can see the win on symmetric openings or
+// other transposing positions (0 with no lookups yet).
+func (t *Transposition) HitRate() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.lookups == 0 {
+		return 0
+	}
+	return float64(t.hits) / float64(t.lookups)
+}
+
+// BackpropagateDAG is the multi-parent counterpart to Backpropagate: it
+// records reward at n and at every node reachable by walking up through
+// parent and sharedParents, breadth-first with a visited set so a node
+// folded in from more than one move order is only updated once per call.
+// The sign still alternates once per hop (the side to move still
+// alternates every ply along any path in an mnk-style game), and a node is
+// visited at the sign of whichever path reaches it first in the
+// breadth-first walk.
+//
+// This is opt-in via Config.BackpropAllParents because it's only meaningful
+// once a Transposition table is actually folding nodes together; without
+// one, sharedParents is always empty and it behaves exactly like
+// Backpropagate. It isn't used by MCTSParallel: virtual loss accounting
+// assumes the single path selectWithVirtualLoss descended, so root-parallel
+// search keeps the single-parent walk (Config.BackpropAllParents is a
+// single-threaded MCTS option, as documented on the field).
+func (n *Node) BackpropagateDAG(reward float64) {
+	type frontier struct {
+		node *Node
+		sign float64
+	}
+	visited := map[*Node]bool{n: true}
+	queue := []frontier{{n, 1.0}}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+
+		cur.node.mu.Lock()
+		cur.node.visits++
+		cur.node.totalReward += reward * cur.sign
+		parents := make([]*Node, 0, 1+len(cur.node.sharedParents))
+		if cur.node.parent != nil {
+			parents = append(parents, cur.node.parent)
+		}
+		parents = append(parents, cur.node.sharedParents...)
+		cur.node.mu.Unlock()
+
+		for _, p := range parents {
+			if !visited[p] {
+				visited[p] = true
+				queue = append(queue, frontier{p, -cur.sign})
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package mcts
+
+import "testing"
+
+// mergeState is a minimal Hashable GameState whose hash XORs in one of two
+// fixed keys per ply, so - like a real Zobrist hash - two different action
+// orders that pick the same two actions land on the same hash. It says
+// nothing about search quality; it only exercises Expand's folding and
+// BackpropagateDAG's multi-parent walk.
+type mergeState struct {
+	hash            uint64
+	depth, maxDepth int
+}
+
+func mergeKey(a Action) uint64 {
+	if a.(int) == 0 {
+		return 0xA
+	}
+	return 0xB
+}
+
+func (s mergeState) GetPossibleActions() []Action { return []Action{0, 1} }
+
+func (s mergeState) PerformAction(a Action) GameState {
+	return mergeState{hash: s.hash ^ mergeKey(a), depth: s.depth + 1, maxDepth: s.maxDepth}
+}
+
+func (s mergeState) IsTerminal() bool   { return s.depth >= s.maxDepth }
+func (s mergeState) GetReward() float64 { return 0 }
+func (s mergeState) Hash() uint64       { return s.hash }
+
+// buildMergeTree expands a 2-ply mergeState tree under table, so the two
+// depth-2 positions reachable by both action orders (0 then 1, and 1 then
+// 0) fold into shared nodes.
+func buildMergeTree(table *Transposition) (root, childA, childB *Node) {
+	root = NewNode(mergeState{maxDepth: 2}, nil, 1.0, nil)
+	root.Expand(UniformEvaluator{}, table)
+	children := root.Children()
+	childA, childB = children[0], children[1]
+	childA.Expand(UniformEvaluator{}, table)
+	childB.Expand(UniformEvaluator{}, table)
+	return root, childA, childB
+}
+
+func TestExpandFoldsTransposingPositions(t *testing.T) {
+	table := NewTransposition()
+	_, childA, childB := buildMergeTree(table)
+
+	grandchildrenA := childA.Children()
+	grandchildrenB := childB.Children()
+	if len(grandchildrenA) != 2 || len(grandchildrenB) != 2 {
+		t.Fatalf("expected 2 children each, got %d and %d", len(grandchildrenA), len(grandchildrenB))
+	}
+
+	// Every grandchild under childB should be the very same Node as one
+	// under childA - the transposition table folding two orders of the
+	// same pair of moves into one shared position.
+	shared := map[*Node]bool{grandchildrenA[0]: true, grandchildrenA[1]: true}
+	for _, g := range grandchildrenB {
+		if !shared[g] {
+			t.Errorf("grandchild %p under childB wasn't folded into childA's tree", g)
+		}
+	}
+
+	// 3 Expand calls (root, childA, childB) each look up both actions: 6
+	// lookups total. Only childB's 2 lookups hit the nodes childA already
+	// created.
+	if hr := table.HitRate(); hr != 2.0/6.0 {
+		t.Errorf("HitRate() = %v, want %v (2 hits of 6 lookups)", hr, 2.0/6.0)
+	}
+}
+
+func TestExpandFoldRecordsSharedParent(t *testing.T) {
+	table := NewTransposition()
+	_, childA, childB := buildMergeTree(table)
+
+	shared := childA.Children()[0]
+	found := false
+	for _, p := range shared.sharedParents {
+		if p == childB {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("folded node's sharedParents = %v, want it to include childB (%p)", shared.sharedParents, childB)
+	}
+}
+
+// TestPUCTValueUsesCallingParentsVisits guards against a regression where
+// PUCTValue read n.parent.Visits() - fixed to whichever parent first
+// created a node - instead of the parent actually passed in. A folded node
+// is reachable from two parents with very different visit counts; using
+// the wrong one silently distorts exploration for every folded node.
+func TestPUCTValueUsesCallingParentsVisits(t *testing.T) {
+	table := NewTransposition()
+	_, childA, childB := buildMergeTree(table)
+	shared := childA.Children()[0]
+
+	childA.visits = 1000
+	childB.visits = 1
+
+	viaA := shared.PUCTValue(1.41, childA)
+	viaB := shared.PUCTValue(1.41, childB)
+	if viaA == viaB {
+		t.Fatalf("PUCTValue() was %v via both childA (visits=1000) and childB (visits=1); want it to depend on which parent is asking", viaA)
+	}
+}
+
+func TestBackpropagateDAGCreditsAllParents(t *testing.T) {
+	table := NewTransposition()
+	root, childA, childB := buildMergeTree(table)
+	shared := childA.Children()[0]
+
+	shared.BackpropagateDAG(1)
+
+	if v := shared.Visits(); v != 1 {
+		t.Errorf("shared.Visits() = %d, want 1", v)
+	}
+	if v := childA.Visits(); v != 1 {
+		t.Errorf("childA.Visits() = %d, want 1 (reached via its own parent link)", v)
+	}
+	if v := childB.Visits(); v != 1 {
+		t.Errorf("childB.Visits() = %d, want 1 (reached via sharedParents)", v)
+	}
+	if v := root.Visits(); v != 1 {
+		t.Errorf("root.Visits() = %d, want 1 (reached through both childA and childB, counted once)", v)
+	}
+}
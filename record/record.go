@@ -0,0 +1,174 @@
+// Package record saves and loads played mnk games in an SGF-inspired text
+// format: a handful of bracketed header properties followed by a
+// semicolon-delimited move sequence, e.g.
+//
+//	PX[MCTS]
+//	PO[Human]
+//	DT[2026-07-25]
+//	SZ[3:3:3]
+//	RE[X+]
+//	;X[aa];O[bb];X[ac];O[bc];X[ab]
+//
+// Move coordinates are two letters, row then column (a=0, b=1, ...), so
+// boards are limited to 26x26 - comfortably more than Gomoku's 15x15.
+// This turns a played game into a reproducible artifact that can be
+// replayed for analysis or fed to an offline MCTS evaluation harness.
+package record
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/TarantulaTechnology/MCTSTicTacToe/game/mnk"
+)
+
+// Game is a complete recorded mnk game: its header metadata plus the
+// moves played, in order, starting from the empty M,N,K position with X
+// to move.
+type Game struct {
+	PlayerX, PlayerO string
+	Date             string
+	Result           string
+	M, N, K          int
+	Moves            []mnk.Action
+}
+
+const maxCoord = 26
+
+func encodeCoord(row, col int) (string, error) {
+	if row < 0 || row >= maxCoord || col < 0 || col >= maxCoord {
+		return "", fmt.Errorf("record: coordinate (%d,%d) is out of the a-z range this format supports", row, col)
+	}
+	return string([]byte{byte('a' + row), byte('a' + col)}), nil
+}
+
+func decodeCoord(s string) (row, col int, err error) {
+	if len(s) != 2 {
+		return 0, 0, fmt.Errorf("record: malformed coordinate %q", s)
+	}
+	row, col = int(s[0]-'a'), int(s[1]-'a')
+	if row < 0 || row >= maxCoord || col < 0 || col >= maxCoord {
+		return 0, 0, fmt.Errorf("record: coordinate %q is out of the a-z range this format supports", s)
+	}
+	return row, col, nil
+}
+
+// Write serializes g as header properties followed by a single line of
+// semicolon-delimited moves.
+func Write(w io.Writer, g Game) error {
+	fmt.Fprintf(w, "PX[%s]\n", g.PlayerX)
+	fmt.Fprintf(w, "PO[%s]\n", g.PlayerO)
+	fmt.Fprintf(w, "DT[%s]\n", g.Date)
+	fmt.Fprintf(w, "SZ[%d:%d:%d]\n", g.M, g.N, g.K)
+	fmt.Fprintf(w, "RE[%s]\n", g.Result)
+
+	var moves strings.Builder
+	player := mnk.X
+	for _, move := range g.Moves {
+		coord, err := encodeCoord(move.Row, move.Col)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(&moves, ";%s[%s]", player, coord)
+		player = player.Other()
+	}
+	_, err := fmt.Fprintln(w, moves.String())
+	return err
+}
+
+// Parse reads a Game back from r in the format Write produces.
+func Parse(r io.Reader) (Game, error) {
+	var g Game
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		switch {
+		case strings.HasPrefix(line, "PX["):
+			g.PlayerX = prop(line, "PX")
+		case strings.HasPrefix(line, "PO["):
+			g.PlayerO = prop(line, "PO")
+		case strings.HasPrefix(line, "DT["):
+			g.Date = prop(line, "DT")
+		case strings.HasPrefix(line, "RE["):
+			g.Result = prop(line, "RE")
+		case strings.HasPrefix(line, "SZ["):
+			m, n, k, err := parseSize(prop(line, "SZ"))
+			if err != nil {
+				return Game{}, err
+			}
+			g.M, g.N, g.K = m, n, k
+		case strings.HasPrefix(line, ";"):
+			moves, err := parseMoves(line)
+			if err != nil {
+				return Game{}, err
+			}
+			g.Moves = moves
+		default:
+			return Game{}, fmt.Errorf("record: unrecognized line %q", line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return Game{}, err
+	}
+	return g, nil
+}
+
+func prop(line, key string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(line, key+"["), "]")
+}
+
+func parseSize(spec string) (m, n, k int, err error) {
+	parts := strings.Split(spec, ":")
+	if len(parts) != 3 {
+		return 0, 0, 0, fmt.Errorf("record: malformed SZ %q, want M:N:K", spec)
+	}
+	vals := make([]int, 3)
+	for i, p := range parts {
+		if vals[i], err = strconv.Atoi(p); err != nil {
+			return 0, 0, 0, fmt.Errorf("record: malformed SZ %q: %w", spec, err)
+		}
+	}
+	return vals[0], vals[1], vals[2], nil
+}
+
+func parseMoves(line string) ([]mnk.Action, error) {
+	var moves []mnk.Action
+	for _, entry := range strings.Split(line, ";") {
+		if entry == "" {
+			continue
+		}
+		open := strings.IndexByte(entry, '[')
+		if open < 0 || !strings.HasSuffix(entry, "]") {
+			return nil, fmt.Errorf("record: malformed move %q", entry)
+		}
+		row, col, err := decodeCoord(entry[open+1 : len(entry)-1])
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, mnk.Action{Row: row, Col: col})
+	}
+	return moves, nil
+}
+
+// Replay plays g's moves from the empty g.M,g.N,g.K position and returns
+// the state reached after each one (len(states) == len(g.Moves)), for
+// analysis or as MCTS training data. It fails if any recorded move is
+// illegal against the position it was played from.
+func Replay(g Game) ([]*mnk.State, error) {
+	state := mnk.New(g.M, g.N, g.K)
+	states := make([]*mnk.State, 0, len(g.Moves))
+	for i, move := range g.Moves {
+		if !state.IsEmpty(move.Row, move.Col) {
+			return nil, fmt.Errorf("record: move %d (%d,%d) is illegal", i, move.Row, move.Col)
+		}
+		state = state.PerformAction(move).(*mnk.State)
+		states = append(states, state)
+	}
+	return states, nil
+}
@@ -0,0 +1,82 @@
+package record
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TarantulaTechnology/MCTSTicTacToe/game/mnk"
+)
+
+func TestWriteParseRoundTrip(t *testing.T) {
+	want := Game{
+		PlayerX: "MCTS",
+		PlayerO: "Human",
+		Date:    "2026-07-25",
+		Result:  "X+",
+		M:       3, N: 3, K: 3,
+		Moves: []mnk.Action{{Row: 0, Col: 0}, {Row: 1, Col: 1}, {Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 0, Col: 2}},
+	}
+
+	var buf strings.Builder
+	if err := Write(&buf, want); err != nil {
+		t.Fatalf("Write() error: %v", err)
+	}
+
+	got, err := Parse(strings.NewReader(buf.String()))
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	if len(got.Moves) != len(want.Moves) {
+		t.Fatalf("Parse() moves = %v, want %v", got.Moves, want.Moves)
+	}
+	if got.PlayerX != want.PlayerX || got.PlayerO != want.PlayerO || got.Date != want.Date || got.Result != want.Result {
+		t.Errorf("Parse() header = %+v, want %+v", got, want)
+	}
+	if got.M != want.M || got.N != want.N || got.K != want.K {
+		t.Errorf("Parse() size = %d,%d,%d, want %d,%d,%d", got.M, got.N, got.K, want.M, want.N, want.K)
+	}
+	for i, move := range want.Moves {
+		if got.Moves[i] != move {
+			t.Errorf("Parse() move %d = %v, want %v", i, got.Moves[i], move)
+		}
+	}
+}
+
+func TestParseRejectsUnrecognizedLine(t *testing.T) {
+	_, err := Parse(strings.NewReader("PX[MCTS]\ngarbage\n"))
+	if err == nil {
+		t.Fatal("Parse() with an unrecognized line, want an error")
+	}
+}
+
+func TestReplayAppliesMovesInOrder(t *testing.T) {
+	g := Game{
+		M: 3, N: 3, K: 3,
+		Moves: []mnk.Action{{Row: 0, Col: 0}, {Row: 1, Col: 1}, {Row: 0, Col: 1}, {Row: 1, Col: 0}, {Row: 0, Col: 2}},
+	}
+
+	states, err := Replay(g)
+	if err != nil {
+		t.Fatalf("Replay() error: %v", err)
+	}
+	if len(states) != len(g.Moves) {
+		t.Fatalf("Replay() returned %d states, want %d", len(states), len(g.Moves))
+	}
+	final := states[len(states)-1]
+	if !final.IsTerminal() {
+		t.Errorf("final state should be terminal (X completed a line):\n%s", final)
+	}
+	if final.GetReward() != -1 {
+		t.Errorf("final GetReward() = %v, want -1", final.GetReward())
+	}
+}
+
+func TestReplayRejectsIllegalMove(t *testing.T) {
+	g := Game{
+		M: 3, N: 3, K: 3,
+		Moves: []mnk.Action{{Row: 0, Col: 0}, {Row: 0, Col: 0}},
+	}
+	if _, err := Replay(g); err == nil {
+		t.Fatal("Replay() with a move onto an occupied square, want an error")
+	}
+}